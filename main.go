@@ -9,6 +9,12 @@ import (
 	"syscall"
 
 	"github.com/spf13/viper"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/metrics"
+	"github.com/NebilMuhe/yaya_webhook/pkg/service"
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+	transporthttp "github.com/NebilMuhe/yaya_webhook/pkg/transport/http"
+	"github.com/NebilMuhe/yaya_webhook/pkg/webhooks"
 )
 
 func main() {
@@ -25,12 +31,51 @@ func main() {
 		Addr:    ":" + viper.GetString("port"),
 	}
 
-	logger.InfoContext(ctx, "Intializing yaya webhook handler...")
-	handler := NewHandler(viper.GetString("secret_key"), logger)
-	logger.InfoContext(ctx, "Yaya webhook handler initialized successfully")
+	logger.InfoContext(ctx, "Connecting to database...")
+	repo, err := store.New(ctx, viper.GetString("database.driver"), viper.GetString("database.dsn"))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to open database", "error", err)
+		os.Exit(1)
+	}
+	defer repo.Close()
+	logger.InfoContext(ctx, "Database connected and migrations applied")
+
+	logger.InfoContext(ctx, "Initializing yaya webhook service...")
+	svc := service.New(
+		viper.GetString("secret_key"),
+		repo,
+		logger,
+		viper.GetDuration("webhook.tolerance"),
+		viper.GetDuration("webhook.skew"),
+		viper.GetDuration("webhook.dedupe_ttl"),
+		viper.GetBool("log.debug_signatures"),
+	)
+	handler := transporthttp.NewHandler(svc)
+	logger.InfoContext(ctx, "Yaya webhook service initialized successfully")
 
 	mux.HandleFunc("GET /healthcheck", handler.HealthCheckHandler)
+	mux.Handle("GET /metrics", metrics.Handler())
 	mux.HandleFunc("POST /webhook", handler.YayayWebhookHandler)
+	mux.HandleFunc("GET /webhook/{id}", handler.GetWebhookHandler)
+	mux.HandleFunc("GET /debug/webhooks", handler.DebugWebhooksHandler)
+
+	logger.InfoContext(ctx, "Initializing webhook fan-out subsystem...")
+	if viper.GetString("admin.api_key") == "" {
+		logger.ErrorContext(ctx, "admin.api_key is not configured; subscription management API will reject all requests")
+	}
+	webhookAPI := webhooks.NewAPI(repo, logger, viper.GetString("admin.api_key"))
+
+	mux.HandleFunc("GET /subscriptions", webhookAPI.SubscriptionsHandler)
+	mux.HandleFunc("POST /subscriptions", webhookAPI.SubscriptionsHandler)
+	mux.HandleFunc("GET /subscriptions/{id}", webhookAPI.SubscriptionHandler)
+	mux.HandleFunc("PUT /subscriptions/{id}", webhookAPI.SubscriptionHandler)
+	mux.HandleFunc("PATCH /subscriptions/{id}", webhookAPI.SubscriptionHandler)
+	mux.HandleFunc("DELETE /subscriptions/{id}", webhookAPI.SubscriptionHandler)
+	mux.HandleFunc("POST /deliveries/{id}/redeliver", webhookAPI.RedeliverHandler)
+
+	deliveryWorker := webhooks.NewWorker(repo, logger)
+	go deliveryWorker.Run(ctx)
+	logger.InfoContext(ctx, "Webhook fan-out subsystem initialized successfully")
 
 	startServer(ctx, server, logger)
 
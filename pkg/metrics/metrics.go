@@ -0,0 +1,45 @@
+// Package metrics holds the Prometheus collectors for webhook ingestion
+// and exposes them over /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// WebhooksReceivedTotal counts inbound webhooks by outcome: accepted,
+// invalid_signature, invalid_timestamp, duplicate, or decode_error.
+var WebhooksReceivedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "yaya_webhooks_received_total",
+	Help: "Total number of inbound webhooks, labelled by processing outcome.",
+}, []string{"outcome"})
+
+// ProcessingSeconds observes how long the webhook handler takes to
+// validate and respond to an inbound request.
+var ProcessingSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "yaya_webhook_processing_seconds",
+	Help:    "Time spent validating and responding to an inbound webhook request.",
+	Buckets: prometheus.DefBuckets,
+})
+
+// InFlightSaves is the number of accepted webhooks currently being
+// persisted and fanned out to subscribers in the background.
+var InFlightSaves = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "yaya_webhook_inflight_saves",
+	Help: "Number of accepted webhooks currently being saved and fanned out.",
+})
+
+// DeliveryQueueDepth is the number of outbound deliveries the fan-out
+// worker found due on its most recent poll.
+var DeliveryQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "yaya_webhook_delivery_queue_depth",
+	Help: "Number of outbound deliveries due for (re)delivery as of the last poll.",
+})
+
+// Handler serves the collected metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
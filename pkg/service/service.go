@@ -0,0 +1,228 @@
+// Package service holds the application logic for ingesting and querying
+// yaya webhooks, independent of any transport. It composes the pure
+// signing functions in pkg/signing with a pkg/store.Repository.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/metrics"
+	"github.com/NebilMuhe/yaya_webhook/pkg/model"
+	"github.com/NebilMuhe/yaya_webhook/pkg/signing"
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+)
+
+// Service is the composition root for webhook ingestion: verifying and
+// recording inbound webhooks, and fanning them out to subscribers.
+type Service struct {
+	SecretKey string
+	Repo      store.Repository
+	Log       *slog.Logger
+
+	Tolerance time.Duration
+	Skew      time.Duration
+	DedupeTTL time.Duration
+
+	// DebugSignatures gates logging of the generated signature and its
+	// concatenated input, configurable via log.debug_signatures. It is
+	// off by default since both values are sensitive.
+	DebugSignatures bool
+}
+
+// New constructs a Service. tolerance, skew and dedupeTTL are the
+// webhook.tolerance, webhook.skew and webhook.dedupe_ttl values; zero
+// values fall back to the same defaults the handler used previously.
+func New(secretKey string, repo store.Repository, log *slog.Logger, tolerance, skew, dedupeTTL time.Duration, debugSignatures bool) *Service {
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	if skew <= 0 {
+		skew = 30 * time.Second
+	}
+	if dedupeTTL <= 0 {
+		dedupeTTL = tolerance + time.Minute
+	}
+
+	return &Service{
+		SecretKey:       secretKey,
+		Repo:            repo,
+		Log:             log,
+		Tolerance:       tolerance,
+		Skew:            skew,
+		DedupeTTL:       dedupeTTL,
+		DebugSignatures: debugSignatures,
+	}
+}
+
+// ErrInvalidTimestamp is returned when a webhook's timestamp falls
+// outside the configured tolerance/skew window.
+var ErrInvalidTimestamp = errInvalidTimestamp{}
+
+type errInvalidTimestamp struct{}
+
+func (errInvalidTimestamp) Error() string { return "invalid timestamp" }
+
+// ErrInvalidSignature is returned when a webhook's signature does not
+// match the one generated for its payload under SecretKey.
+var ErrInvalidSignature = errInvalidSignature{}
+
+type errInvalidSignature struct{}
+
+func (errInvalidSignature) Error() string { return "invalid signature" }
+
+// IngestResult reports what happened to a webhook accepted for ingestion.
+type IngestResult struct {
+	Duplicate bool
+}
+
+// Ingest verifies payload against signature, records it for replay
+// protection, and — unless it's a duplicate — asynchronously persists it
+// and fans it out to matching subscribers. sourceIP is the originating
+// request's address, used only for the audit log.
+func (s *Service) Ingest(ctx context.Context, payload model.YayaWebhook, signature, sourceIP string) (IngestResult, error) {
+	start := time.Now()
+	outcome := "accepted"
+	defer func() {
+		s.audit(ctx, payload.ID, outcome, start, signature, sourceIP)
+	}()
+
+	if s.DebugSignatures {
+		s.Log.DebugContext(ctx, "generated signature for comparison",
+			"webhook_id", payload.ID,
+			"generated_signature", signing.GenerateSignature(s.SecretKey, payload),
+			"received_signature", signature)
+	}
+
+	if !signing.ValidateTimestamp(payload.TimeStamp, s.Tolerance, s.Skew) {
+		outcome = "invalid_timestamp"
+		return IngestResult{}, ErrInvalidTimestamp
+	}
+
+	if !signing.VerifySignature(s.SecretKey, signature, payload) {
+		outcome = "invalid_signature"
+		return IngestResult{}, ErrInvalidSignature
+	}
+
+	duplicate, err := s.Repo.MarkProcessed(ctx, payload.ID, signature, s.DedupeTTL)
+	if err != nil {
+		outcome = "error"
+		return IngestResult{}, err
+	}
+	if duplicate {
+		outcome = "duplicate"
+		return IngestResult{Duplicate: true}, nil
+	}
+
+	metrics.InFlightSaves.Inc()
+	go func() {
+		defer metrics.InFlightSaves.Dec()
+
+		if err := s.save(context.Background(), payload); err != nil {
+			s.Log.ErrorContext(context.Background(), "failed to save webhook to database", "error", err, "webhook_id", payload.ID)
+		} else {
+			s.Log.InfoContext(context.Background(), "webhook saved to database successfully", "webhook_id", payload.ID)
+		}
+
+		s.fanOut(context.Background(), payload)
+	}()
+
+	return IngestResult{}, nil
+}
+
+// RecordDecodeError records the audit event and metric for a request
+// whose body could not be decoded into a webhook payload.
+func (s *Service) RecordDecodeError(ctx context.Context, sourceIP string) {
+	s.audit(ctx, "", "decode_error", time.Now(), "", sourceIP)
+}
+
+// RecordMissingSignature records the audit event and metric for a
+// request that arrived without a YAYA-SIGNATURE header.
+func (s *Service) RecordMissingSignature(ctx context.Context, sourceIP string) {
+	s.audit(ctx, "", "invalid_signature", time.Now(), "", sourceIP)
+}
+
+// audit emits a single structured audit event for the request and
+// records its outcome and latency as metrics. signature is truncated to
+// its first 8 characters so the full HMAC never reaches logs.
+func (s *Service) audit(ctx context.Context, webhookID, outcome string, start time.Time, signature, sourceIP string) {
+	latency := time.Since(start)
+
+	metrics.WebhooksReceivedTotal.WithLabelValues(outcome).Inc()
+	metrics.ProcessingSeconds.Observe(latency.Seconds())
+
+	s.Log.InfoContext(ctx, "webhook processed",
+		"webhook_id", webhookID,
+		"outcome", outcome,
+		"latency_ms", latency.Milliseconds(),
+		"signature_prefix", signaturePrefix(signature),
+		"source_ip", sourceIP,
+	)
+}
+
+func signaturePrefix(signature string) string {
+	if len(signature) <= 8 {
+		return signature
+	}
+	return signature[:8]
+}
+
+func (s *Service) save(ctx context.Context, webhook model.YayaWebhook) error {
+	return s.Repo.SaveWebhook(ctx, store.Webhook{
+		ID:            webhook.ID,
+		Amount:        webhook.Amount.String(),
+		Currency:      string(webhook.Currency),
+		CreatedAtTime: webhook.CreatedAtTime,
+		TimeStamp:     webhook.TimeStamp,
+		Cause:         webhook.Cause,
+		FullName:      webhook.FullName,
+		AccountName:   webhook.AccountName,
+		InvoiceURL:    webhook.InvoiceURL,
+	})
+}
+
+// fanOut enqueues a pending delivery for every subscription interested in
+// webhook's cause, to be sent by the background delivery worker.
+func (s *Service) fanOut(ctx context.Context, webhook model.YayaWebhook) {
+	subs, err := s.Repo.MatchingSubscriptions(ctx, webhook.Cause)
+	if err != nil {
+		s.Log.ErrorContext(ctx, "failed to load matching subscriptions", "error", err, "webhook_id", webhook.ID)
+		return
+	}
+
+	body, err := json.Marshal(webhook)
+	if err != nil {
+		s.Log.ErrorContext(ctx, "failed to marshal webhook for fan-out", "error", err, "webhook_id", webhook.ID)
+		return
+	}
+
+	signable := signing.ConcatFields(
+		webhook.ID,
+		webhook.Amount.String(),
+		string(webhook.Currency),
+		webhook.CreatedAtTime,
+		webhook.TimeStamp,
+		webhook.Cause,
+		webhook.FullName,
+		webhook.AccountName,
+		webhook.InvoiceURL,
+	)
+
+	for _, sub := range subs {
+		if err := s.Repo.EnqueueDelivery(ctx, sub, string(body), signable); err != nil {
+			s.Log.ErrorContext(ctx, "failed to enqueue delivery", "error", err, "webhook_id", webhook.ID, "subscription_id", sub.ID)
+		}
+	}
+}
+
+// GetWebhook retrieves a previously ingested webhook by ID.
+func (s *Service) GetWebhook(ctx context.Context, id string) (store.Webhook, error) {
+	return s.Repo.GetWebhookByID(ctx, id)
+}
+
+// ListWebhooks returns a page of ingested webhooks matching filter.
+func (s *Service) ListWebhooks(ctx context.Context, filter store.Filter, pagination store.Pagination) (store.WebhookPage, error) {
+	return s.Repo.ListWebhooks(ctx, filter, pagination)
+}
@@ -0,0 +1,67 @@
+// Package signing implements the HMAC scheme used to authenticate
+// inbound yaya webhooks and to re-sign outbound fan-out deliveries. Every
+// function here is pure: it takes a secret and the data to sign/verify
+// and returns a result, with no I/O or logging side effects.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/model"
+)
+
+// ConcatFields reproduces the exact field concatenation order used to
+// sign a yaya webhook: ID|Amount|Currency|CreatedAtTime|TimeStamp|Cause|
+// FullName|AccountName|InvoiceURL, with no separators. Both inbound
+// verification and outbound re-signing (with a different secret) build
+// on this single definition.
+func ConcatFields(id, amount, currency string, createdAtTime, timeStamp int64, cause, fullName, accountName, invoiceURL string) string {
+	return fmt.Sprintf("%s%s%s%d%d%s%s%s%s",
+		id, amount, currency, createdAtTime, timeStamp, cause, fullName, accountName, invoiceURL,
+	)
+}
+
+// Sign computes the HMAC-SHA256 signature of signable, keyed with secret.
+func Sign(secret, signable string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(signable))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GenerateSignature computes the YAYA-SIGNATURE value for payload under secret.
+func GenerateSignature(secret string, payload model.YayaWebhook) string {
+	return Sign(secret, concatWebhook(payload))
+}
+
+// VerifySignature reports whether signature matches the one generated
+// for payload under secret.
+func VerifySignature(secret, signature string, payload model.YayaWebhook) bool {
+	return hmac.Equal([]byte(signature), []byte(GenerateSignature(secret, payload)))
+}
+
+func concatWebhook(payload model.YayaWebhook) string {
+	return ConcatFields(
+		payload.ID,
+		payload.Amount.String(),
+		string(payload.Currency),
+		payload.CreatedAtTime,
+		payload.TimeStamp,
+		payload.Cause,
+		payload.FullName,
+		payload.AccountName,
+		payload.InvoiceURL,
+	)
+}
+
+// ValidateTimestamp reports whether timestamp falls within [-skew,
+// +tolerance] of now, where skew absorbs clock drift that makes a
+// timestamp appear slightly in the future.
+func ValidateTimestamp(timestamp int64, tolerance, skew time.Duration) bool {
+	now := time.Now().Unix()
+	diff := now - timestamp
+	return diff >= -int64(skew.Seconds()) && diff <= int64(tolerance.Seconds())
+}
@@ -0,0 +1,95 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/model"
+)
+
+func TestConcatFields_Order(t *testing.T) {
+	got := ConcatFields("wh-1", "100", "ETB", 1700000000, 1700000005, "deposit", "Jane Doe", "jane", "https://example.com/invoice/1")
+	want := "wh-1100ETB17000000001700000005depositJane Doejanehttps://example.com/invoice/1"
+
+	if got != want {
+		t.Fatalf("ConcatFields order changed:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestGenerateSignature_GoldenVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		payload model.YayaWebhook
+		want    string
+	}{
+		{
+			name:   "basic deposit",
+			secret: "top-secret",
+			payload: model.YayaWebhook{
+				ID:            "wh-1",
+				Amount:        decimal.NewFromInt(100),
+				Currency:      model.ETB,
+				CreatedAtTime: 1700000000,
+				TimeStamp:     1700000005,
+				Cause:         "deposit",
+				FullName:      "Jane Doe",
+				AccountName:   "jane",
+				InvoiceURL:    "https://example.com/invoice/1",
+			},
+			want: "80910efc23fc155beab1a79f91a5f32dd4ba662bd3c43ac52168395b9ad9db2c",
+		},
+		{
+			name:   "withdrawal, different secret",
+			secret: "secret",
+			payload: model.YayaWebhook{
+				ID:            "wh-2",
+				Amount:        decimal.NewFromInt(50),
+				Currency:      model.ETB,
+				CreatedAtTime: 1700000000,
+				TimeStamp:     1700000000,
+				Cause:         "withdrawal",
+				FullName:      "John Roe",
+				AccountName:   "john",
+				InvoiceURL:    "https://example.com/invoice/2",
+			},
+			want: "60c9e8df91730ad8643d52e0c55ed8bfbac95bd696ca4b116597657f00192a65",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateSignature(tt.secret, tt.payload)
+			if got != tt.want {
+				t.Fatalf("signature changed for fixed field order and secret:\n got:  %s\n want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := model.YayaWebhook{
+		ID:            "wh-2",
+		Amount:        decimal.NewFromInt(50),
+		Currency:      model.ETB,
+		CreatedAtTime: 1700000000,
+		TimeStamp:     1700000000,
+		Cause:         "withdrawal",
+		FullName:      "John Roe",
+		AccountName:   "john",
+		InvoiceURL:    "https://example.com/invoice/2",
+	}
+
+	sig := GenerateSignature("secret", payload)
+
+	if !VerifySignature("secret", sig, payload) {
+		t.Fatal("expected matching secret and signature to verify")
+	}
+	if VerifySignature("wrong-secret", sig, payload) {
+		t.Fatal("expected mismatched secret to fail verification")
+	}
+	if VerifySignature("secret", "deadbeef", payload) {
+		t.Fatal("expected mismatched signature to fail verification")
+	}
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"sort"
+)
+
+//go:embed migrations/sqlite/*.sql migrations/postgres/*.sql
+var migrationFiles embed.FS
+
+// migrate applies every migration under migrations/<dialect> that has
+// not yet been recorded in schema_migrations, in filename order. It is
+// safe to call on every startup. param formats a 1-based positional
+// placeholder for the target dialect ("?" for SQLite, "$1"-style for
+// Postgres).
+func migrate(ctx context.Context, db *sql.DB, dialect, createMigrationsTable string, param func(n int) string) error {
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	dir := "migrations/" + dialect
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		applied, err := migrationApplied(ctx, db, name, param)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		contents, err := migrationFiles.ReadFile(dir + "/" + name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start migration transaction: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, string(contents)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %s: %w", name, err)
+		}
+
+		insert := fmt.Sprintf(`INSERT INTO schema_migrations (name) VALUES (%s)`, param(1))
+		if _, err := tx.ExecContext(ctx, insert, name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %s: %w", name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func migrationApplied(ctx context.Context, db *sql.DB, name string, param func(n int) string) (bool, error) {
+	var count int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM schema_migrations WHERE name = %s`, param(1))
+	err := db.QueryRowContext(ctx, query, name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check migration status for %s: %w", name, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import "time"
+
+// Webhook is the persisted representation of a single inbound yaya
+// webhook event.
+type Webhook struct {
+	ID            string
+	Amount        string
+	Currency      string
+	CreatedAtTime int64
+	TimeStamp     int64
+	Cause         string
+	FullName      string
+	AccountName   string
+	InvoiceURL    string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Filter narrows a ListWebhooks call to webhooks created within a range.
+// A zero time.Time means the bound is open.
+type Filter struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Pagination is a cursor-based page request. Cursor is opaque to the
+// caller; it is the CreatedAt of the last webhook seen on the previous
+// page, RFC3339-encoded.
+type Pagination struct {
+	Limit  int
+	Cursor string
+}
+
+// WebhookPage is one page of a ListWebhooks result.
+type WebhookPage struct {
+	Webhooks   []Webhook
+	NextCursor string
+}
+
+// Subscription represents a downstream consumer registered to receive
+// fan-out deliveries for a set of event causes.
+type Subscription struct {
+	ID        string
+	URL       string
+	Events    []string
+	Secret    string
+	CreatedAt time.Time
+}
+
+// Matches reports whether the subscription should receive an event with
+// the given cause. A subscription with no events configured, or one
+// containing "*", matches everything.
+func (s Subscription) Matches(cause string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == "*" || event == cause {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a single attempt (or pending attempt) to deliver a webhook
+// payload to a subscription.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	Payload        string
+	Signable       string
+	PayloadHash    string
+	Attempt        int
+	ResponseStatus int
+	ResponseBody   string
+	NextAttemptAt  time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,352 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const postgresCreateMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		name TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ DEFAULT now()
+	)
+`
+
+// postgresRepository is a Repository backed by a pgxpool.Pool.
+type postgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres opens a connection pool to the Postgres database at dsn
+// and applies any pending migrations.
+func NewPostgres(ctx context.Context, dsn string) (Repository, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres pool: %w", err)
+	}
+
+	// The embedded migration runner is written against database/sql, so
+	// migrations run once through the pgx stdlib adapter over the same DSN.
+	db := stdlib.OpenDB(*pool.Config().ConnConfig)
+	err = migrate(ctx, db, "postgres", postgresCreateMigrationsTable, func(n int) string { return fmt.Sprintf("$%d", n) })
+	db.Close()
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &postgresRepository{pool: pool}, nil
+}
+
+func (r *postgresRepository) Close() error {
+	r.pool.Close()
+	return nil
+}
+
+func (r *postgresRepository) SaveWebhook(ctx context.Context, webhook Webhook) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO webhooks (
+			id, amount, currency, created_at_time, timestamp,
+			cause, full_name, account_name, invoice_url, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			amount = excluded.amount,
+			currency = excluded.currency,
+			created_at_time = excluded.created_at_time,
+			timestamp = excluded.timestamp,
+			cause = excluded.cause,
+			full_name = excluded.full_name,
+			account_name = excluded.account_name,
+			invoice_url = excluded.invoice_url,
+			updated_at = excluded.updated_at
+	`,
+		webhook.ID, webhook.Amount, webhook.Currency, webhook.CreatedAtTime, webhook.TimeStamp,
+		webhook.Cause, webhook.FullName, webhook.AccountName, webhook.InvoiceURL, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresRepository) GetWebhookByID(ctx context.Context, id string) (Webhook, error) {
+	var w Webhook
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, amount, currency, created_at_time, timestamp, cause, full_name, account_name, invoice_url, created_at, updated_at
+		FROM webhooks WHERE id = $1`, id,
+	).Scan(&w.ID, &w.Amount, &w.Currency, &w.CreatedAtTime, &w.TimeStamp, &w.Cause, &w.FullName, &w.AccountName, &w.InvoiceURL, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		// pgxpool reports a missing row as pgx.ErrNoRows, a distinct
+		// sentinel from database/sql's; translate it so callers can
+		// check for sql.ErrNoRows regardless of the configured driver.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Webhook{}, sql.ErrNoRows
+		}
+		return Webhook{}, err
+	}
+
+	return w, nil
+}
+
+func (r *postgresRepository) ListWebhooks(ctx context.Context, filter Filter, pagination Pagination) (WebhookPage, error) {
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT id, amount, currency, created_at_time, timestamp, cause, full_name, account_name, invoice_url, created_at, updated_at FROM webhooks WHERE true`
+	var args []any
+	next := func() string {
+		args = append(args, nil)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		placeholder := next()
+		args[len(args)-1] = filter.CreatedAfter
+		query += fmt.Sprintf(" AND created_at >= %s", placeholder)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		placeholder := next()
+		args[len(args)-1] = filter.CreatedBefore
+		query += fmt.Sprintf(" AND created_at <= %s", placeholder)
+	}
+	if pagination.Cursor != "" {
+		cursor, err := time.Parse(time.RFC3339, pagination.Cursor)
+		if err != nil {
+			return WebhookPage{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		placeholder := next()
+		args[len(args)-1] = cursor
+		query += fmt.Sprintf(" AND created_at < %s", placeholder)
+	}
+
+	placeholder := next()
+	args[len(args)-1] = limit + 1
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT %s", placeholder)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return WebhookPage{}, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var page WebhookPage
+	for rows.Next() {
+		var w Webhook
+		if err := rows.Scan(&w.ID, &w.Amount, &w.Currency, &w.CreatedAtTime, &w.TimeStamp, &w.Cause, &w.FullName, &w.AccountName, &w.InvoiceURL, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return WebhookPage{}, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		page.Webhooks = append(page.Webhooks, w)
+	}
+
+	if len(page.Webhooks) > limit {
+		page.NextCursor = page.Webhooks[limit-1].CreatedAt.Format(time.RFC3339)
+		page.Webhooks = page.Webhooks[:limit]
+	}
+
+	return page, nil
+}
+
+func (r *postgresRepository) MarkProcessed(ctx context.Context, id, signature string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM processed_webhooks WHERE expires_at < $1`, now); err != nil {
+		return false, fmt.Errorf("failed to sweep expired processed webhooks: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO processed_webhooks (id, signature, expires_at) VALUES ($1, $2, $3) ON CONFLICT (id, signature) DO NOTHING`,
+		id, signature, now.Add(ttl),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record processed webhook: %w", err)
+	}
+
+	return tag.RowsAffected() == 0, nil
+}
+
+func (r *postgresRepository) CreateSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	if sub.ID == "" {
+		sub.ID = uuid.NewString()
+	}
+	sub.CreatedAt = time.Now()
+
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO subscriptions (id, url, events, secret, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		sub.ID, sub.URL, strings.Join(sub.Events, ","), sub.Secret, sub.CreatedAt,
+	)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to insert subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *postgresRepository) GetSubscription(ctx context.Context, id string) (Subscription, error) {
+	var sub Subscription
+	var events string
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, url, events, secret, created_at FROM subscriptions WHERE id = $1`, id,
+	).Scan(&sub.ID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt)
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub.Events = splitEvents(events)
+
+	return sub, nil
+}
+
+func (r *postgresRepository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, url, events, secret, created_at FROM subscriptions ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var events string
+		if err := rows.Scan(&sub.ID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		sub.Events = splitEvents(events)
+		subs = append(subs, sub)
+	}
+
+	return subs, nil
+}
+
+func (r *postgresRepository) MatchingSubscriptions(ctx context.Context, cause string) ([]Subscription, error) {
+	all, err := r.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Subscription
+	for _, sub := range all {
+		if sub.Matches(cause) {
+			matched = append(matched, sub)
+		}
+	}
+
+	return matched, nil
+}
+
+func (r *postgresRepository) UpdateSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE subscriptions SET url = $1, events = $2, secret = $3 WHERE id = $4`,
+		sub.URL, strings.Join(sub.Events, ","), sub.Secret, sub.ID,
+	)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return Subscription{}, sql.ErrNoRows
+	}
+
+	return r.GetSubscription(ctx, sub.ID)
+}
+
+func (r *postgresRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) EnqueueDelivery(ctx context.Context, sub Subscription, payload, signable string) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO deliveries (id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at) VALUES ($1, $2, $3, $4, $5, 0, $6)`,
+		uuid.NewString(), sub.ID, payload, signable, sha256Hex(payload), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) DueDeliveries(ctx context.Context) ([]Delivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at
+		 FROM deliveries WHERE delivered_at IS NULL AND next_attempt_at <= $1`,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.Payload, &d.Signable, &d.PayloadHash, &d.Attempt, &d.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func (r *postgresRepository) GetDelivery(ctx context.Context, id string) (Delivery, error) {
+	var d Delivery
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at FROM deliveries WHERE id = $1`, id,
+	).Scan(&d.ID, &d.SubscriptionID, &d.Payload, &d.Signable, &d.PayloadHash, &d.Attempt, &d.NextAttemptAt)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return d, nil
+}
+
+func (r *postgresRepository) RecordAttempt(ctx context.Context, id string, status int, body string, delivered bool, nextAttemptAt time.Time) error {
+	var err error
+	if delivered {
+		_, err = r.pool.Exec(ctx,
+			`UPDATE deliveries SET attempt = attempt + 1, response_status = $1, response_body = $2, delivered_at = $3 WHERE id = $4`,
+			status, body, time.Now(), id,
+		)
+	} else {
+		_, err = r.pool.Exec(ctx,
+			`UPDATE deliveries SET attempt = attempt + 1, response_status = $1, response_body = $2, next_attempt_at = $3 WHERE id = $4`,
+			status, body, nextAttemptAt, id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ResetForRedelivery(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE deliveries SET next_attempt_at = $1, delivered_at = NULL WHERE id = $2`,
+		time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset delivery: %w", err)
+	}
+	return nil
+}
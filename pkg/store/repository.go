@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is the persistence boundary for the webhook receiver. It is
+// implemented once per supported database driver (see NewSQLite and
+// NewPostgres) and selected at startup via the database.driver config key.
+type Repository interface {
+	SaveWebhook(ctx context.Context, webhook Webhook) error
+	GetWebhookByID(ctx context.Context, id string) (Webhook, error)
+	ListWebhooks(ctx context.Context, filter Filter, pagination Pagination) (WebhookPage, error)
+
+	// MarkProcessed atomically records that (id, signature) has been
+	// processed. It reports true if that pair was already recorded and
+	// has not yet expired, i.e. this call is a replay.
+	MarkProcessed(ctx context.Context, id, signature string, ttl time.Duration) (duplicate bool, err error)
+
+	CreateSubscription(ctx context.Context, sub Subscription) (Subscription, error)
+	GetSubscription(ctx context.Context, id string) (Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	MatchingSubscriptions(ctx context.Context, cause string) ([]Subscription, error)
+	UpdateSubscription(ctx context.Context, sub Subscription) (Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+
+	EnqueueDelivery(ctx context.Context, sub Subscription, payload, signable string) error
+	DueDeliveries(ctx context.Context) ([]Delivery, error)
+	GetDelivery(ctx context.Context, id string) (Delivery, error)
+	RecordAttempt(ctx context.Context, id string, status int, body string, delivered bool, nextAttemptAt time.Time) error
+	ResetForRedelivery(ctx context.Context, id string) error
+
+	Close() error
+}
+
+// New opens a pooled Repository for the configured driver. Supported
+// drivers are "sqlite" (default) and "postgres".
+func New(ctx context.Context, driver, dsn string) (Repository, error) {
+	switch driver {
+	case "", "sqlite", "sqlite3":
+		return NewSQLite(ctx, dsn)
+	case "postgres", "postgresql", "pgx":
+		return NewPostgres(ctx, dsn)
+	default:
+		return nil, ErrUnsupportedDriver(driver)
+	}
+}
+
+// ErrUnsupportedDriver reports an unrecognized database.driver value.
+type ErrUnsupportedDriver string
+
+func (e ErrUnsupportedDriver) Error() string {
+	return "storage: unsupported database driver " + string(e)
+}
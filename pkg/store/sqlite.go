@@ -0,0 +1,351 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteCreateMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		name TEXT PRIMARY KEY,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+// sqliteRepository is a Repository backed by a single pooled *sql.DB
+// against a SQLite file.
+type sqliteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLite opens a pooled connection to the SQLite database at dsn and
+// applies any pending migrations.
+func NewSQLite(ctx context.Context, dsn string) (Repository, error) {
+	if dsn == "" {
+		dsn = "./yaya_webhooks.db"
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if err := migrate(ctx, db, "sqlite", sqliteCreateMigrationsTable, func(n int) string { return "?" }); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqliteRepository{db: db}, nil
+}
+
+func (r *sqliteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *sqliteRepository) SaveWebhook(ctx context.Context, webhook Webhook) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhooks (
+			id, amount, currency, created_at_time, timestamp,
+			cause, full_name, account_name, invoice_url, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			amount = excluded.amount,
+			currency = excluded.currency,
+			created_at_time = excluded.created_at_time,
+			timestamp = excluded.timestamp,
+			cause = excluded.cause,
+			full_name = excluded.full_name,
+			account_name = excluded.account_name,
+			invoice_url = excluded.invoice_url,
+			updated_at = excluded.updated_at
+	`,
+		webhook.ID, webhook.Amount, webhook.Currency, webhook.CreatedAtTime, webhook.TimeStamp,
+		webhook.Cause, webhook.FullName, webhook.AccountName, webhook.InvoiceURL, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *sqliteRepository) GetWebhookByID(ctx context.Context, id string) (Webhook, error) {
+	var w Webhook
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, amount, currency, created_at_time, timestamp, cause, full_name, account_name, invoice_url, created_at, updated_at
+		FROM webhooks WHERE id = ?`, id,
+	).Scan(&w.ID, &w.Amount, &w.Currency, &w.CreatedAtTime, &w.TimeStamp, &w.Cause, &w.FullName, &w.AccountName, &w.InvoiceURL, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return w, nil
+}
+
+func (r *sqliteRepository) ListWebhooks(ctx context.Context, filter Filter, pagination Pagination) (WebhookPage, error) {
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT id, amount, currency, created_at_time, timestamp, cause, full_name, account_name, invoice_url, created_at, updated_at FROM webhooks WHERE 1=1`
+	var args []any
+
+	if !filter.CreatedAfter.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query += ` AND created_at <= ?`
+		args = append(args, filter.CreatedBefore)
+	}
+	if pagination.Cursor != "" {
+		cursor, err := time.Parse(time.RFC3339, pagination.Cursor)
+		if err != nil {
+			return WebhookPage{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query += ` AND created_at < ?`
+		args = append(args, cursor)
+	}
+
+	query += ` ORDER BY created_at DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return WebhookPage{}, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var page WebhookPage
+	for rows.Next() {
+		var w Webhook
+		if err := rows.Scan(&w.ID, &w.Amount, &w.Currency, &w.CreatedAtTime, &w.TimeStamp, &w.Cause, &w.FullName, &w.AccountName, &w.InvoiceURL, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return WebhookPage{}, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		page.Webhooks = append(page.Webhooks, w)
+	}
+
+	if len(page.Webhooks) > limit {
+		page.NextCursor = page.Webhooks[limit-1].CreatedAt.Format(time.RFC3339)
+		page.Webhooks = page.Webhooks[:limit]
+	}
+
+	return page, nil
+}
+
+func (r *sqliteRepository) MarkProcessed(ctx context.Context, id, signature string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM processed_webhooks WHERE expires_at < ?`, now); err != nil {
+		return false, fmt.Errorf("failed to sweep expired processed webhooks: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO processed_webhooks (id, signature, expires_at) VALUES (?, ?, ?) ON CONFLICT (id, signature) DO NOTHING`,
+		id, signature, now.Add(ttl),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record processed webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to determine processed webhook insert result: %w", err)
+	}
+
+	return rows == 0, nil
+}
+
+func (r *sqliteRepository) CreateSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	if sub.ID == "" {
+		sub.ID = uuid.NewString()
+	}
+	sub.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO subscriptions (id, url, events, secret, created_at) VALUES (?, ?, ?, ?, ?)`,
+		sub.ID, sub.URL, strings.Join(sub.Events, ","), sub.Secret, sub.CreatedAt,
+	)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to insert subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *sqliteRepository) GetSubscription(ctx context.Context, id string) (Subscription, error) {
+	var sub Subscription
+	var events string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, url, events, secret, created_at FROM subscriptions WHERE id = ?`, id,
+	).Scan(&sub.ID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt)
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub.Events = splitEvents(events)
+
+	return sub, nil
+}
+
+func (r *sqliteRepository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, url, events, secret, created_at FROM subscriptions ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var events string
+		if err := rows.Scan(&sub.ID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		sub.Events = splitEvents(events)
+		subs = append(subs, sub)
+	}
+
+	return subs, nil
+}
+
+func (r *sqliteRepository) MatchingSubscriptions(ctx context.Context, cause string) ([]Subscription, error) {
+	all, err := r.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Subscription
+	for _, sub := range all {
+		if sub.Matches(cause) {
+			matched = append(matched, sub)
+		}
+	}
+
+	return matched, nil
+}
+
+func (r *sqliteRepository) UpdateSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE subscriptions SET url = ?, events = ?, secret = ? WHERE id = ?`,
+		sub.URL, strings.Join(sub.Events, ","), sub.Secret, sub.ID,
+	)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	if affected == 0 {
+		return Subscription{}, sql.ErrNoRows
+	}
+
+	return r.GetSubscription(ctx, sub.ID)
+}
+
+func (r *sqliteRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteRepository) EnqueueDelivery(ctx context.Context, sub Subscription, payload, signable string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO deliveries (id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at) VALUES (?, ?, ?, ?, ?, 0, ?)`,
+		uuid.NewString(), sub.ID, payload, signable, sha256Hex(payload), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteRepository) DueDeliveries(ctx context.Context) ([]Delivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at
+		 FROM deliveries WHERE delivered_at IS NULL AND next_attempt_at <= ?`,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.Payload, &d.Signable, &d.PayloadHash, &d.Attempt, &d.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func (r *sqliteRepository) GetDelivery(ctx context.Context, id string) (Delivery, error) {
+	var d Delivery
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, subscription_id, payload, signable, payload_hash, attempt, next_attempt_at FROM deliveries WHERE id = ?`, id,
+	).Scan(&d.ID, &d.SubscriptionID, &d.Payload, &d.Signable, &d.PayloadHash, &d.Attempt, &d.NextAttemptAt)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return d, nil
+}
+
+func (r *sqliteRepository) RecordAttempt(ctx context.Context, id string, status int, body string, delivered bool, nextAttemptAt time.Time) error {
+	var err error
+	if delivered {
+		_, err = r.db.ExecContext(ctx,
+			`UPDATE deliveries SET attempt = attempt + 1, response_status = ?, response_body = ?, delivered_at = ? WHERE id = ?`,
+			status, body, time.Now(), id,
+		)
+	} else {
+		_, err = r.db.ExecContext(ctx,
+			`UPDATE deliveries SET attempt = attempt + 1, response_status = ?, response_body = ?, next_attempt_at = ? WHERE id = ?`,
+			status, body, nextAttemptAt, id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteRepository) ResetForRedelivery(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE deliveries SET next_attempt_at = ?, delivered_at = NULL WHERE id = ?`,
+		time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset delivery: %w", err)
+	}
+	return nil
+}
+
+func splitEvents(events string) []string {
+	if events == "" {
+		return nil
+	}
+	return strings.Split(events, ",")
+}
@@ -0,0 +1,184 @@
+// Package http exposes the Service as HTTP handlers.
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/model"
+	"github.com/NebilMuhe/yaya_webhook/pkg/service"
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+)
+
+// Handler adapts a *service.Service to net/http.
+type Handler struct {
+	svc *service.Service
+}
+
+// NewHandler returns a Handler backed by svc.
+func NewHandler(svc *service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(model.Response{
+		StatusCode: http.StatusOK,
+		Message:    "Server is up and running",
+	})
+}
+
+func (h *Handler) YayayWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var req model.YayaWebhook
+
+	ip := sourceIP(r)
+
+	signature := r.Header.Get("YAYA-SIGNATURE")
+	if signature == "" {
+		h.svc.RecordMissingSignature(r.Context(), ip)
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusBadRequest,
+			Error:      "signature is missing",
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.svc.RecordDecodeError(r.Context(), ip)
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusBadRequest,
+			Error:      "invalid data",
+		})
+		return
+	}
+
+	result, err := h.svc.Ingest(r.Context(), req, signature, ip)
+	switch err {
+	case nil:
+		if result.Duplicate {
+			json.NewEncoder(w).Encode(model.Response{
+				StatusCode: http.StatusOK,
+				Message:    "duplicate, already processed",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusOK,
+			Message:    "Webhook received successfully",
+		})
+	case service.ErrInvalidTimestamp:
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusBadRequest,
+			Error:      "invalid timestamp",
+		})
+	case service.ErrInvalidSignature:
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusBadRequest,
+			Error:      "invalid signature",
+		})
+	default:
+		h.svc.Log.ErrorContext(r.Context(), "failed to process webhook", "error", err, "webhook_id", req.ID)
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusInternalServerError,
+			Error:      "failed to process webhook",
+		})
+	}
+}
+
+// GetWebhookHandler retrieves a webhook by ID from the URL path.
+func (h *Handler) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		json.NewEncoder(w).Encode(model.Response{
+			StatusCode: http.StatusBadRequest,
+			Error:      "webhook ID is required",
+		})
+		return
+	}
+
+	webhook, err := h.svc.GetWebhook(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			json.NewEncoder(w).Encode(model.Response{
+				StatusCode: http.StatusNotFound,
+				Error:      "webhook not found",
+			})
+			return
+		}
+		h.svc.Log.ErrorContext(r.Context(), "failed to query webhook", "error", err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// DebugWebhooksHandler shows all stored webhooks for debugging. Supports
+// ?limit= (page size, default 50), ?cursor= (opaque page cursor from a
+// previous response), and ?created_after=/?created_before= (RFC3339)
+// range filters.
+func (h *Handler) DebugWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var filter store.Filter
+	if createdAfter := query.Get("created_after"); createdAfter != "" {
+		t, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			http.Error(w, "invalid created_after", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedAfter = t
+	}
+	if createdBefore := query.Get("created_before"); createdBefore != "" {
+		t, err := time.Parse(time.RFC3339, createdBefore)
+		if err != nil {
+			http.Error(w, "invalid created_before", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedBefore = t
+	}
+
+	pagination := store.Pagination{Cursor: query.Get("cursor")}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		pagination.Limit = n
+	}
+
+	page, err := h.svc.ListWebhooks(r.Context(), filter, pagination)
+	if err != nil {
+		h.svc.Log.ErrorContext(r.Context(), "failed to query webhooks", "error", err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := map[string]interface{}{
+		"count":       len(page.Webhooks),
+		"webhooks":    page.Webhooks,
+		"next_cursor": page.NextCursor,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.svc.Log.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		http.Error(w, "Encoding error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// sourceIP returns the request's originating address without its port,
+// falling back to the raw RemoteAddr if it can't be split.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/model"
+	"github.com/NebilMuhe/yaya_webhook/pkg/service"
+	"github.com/NebilMuhe/yaya_webhook/pkg/signing"
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+)
+
+// fakeStore is an in-memory store.Repository stub for exercising the HTTP
+// handlers without a real database.
+type fakeStore struct {
+	mu        sync.Mutex
+	webhooks  map[string]store.Webhook
+	processed map[string]bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		webhooks:  make(map[string]store.Webhook),
+		processed: make(map[string]bool),
+	}
+}
+
+func (f *fakeStore) SaveWebhook(ctx context.Context, webhook store.Webhook) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (f *fakeStore) GetWebhookByID(ctx context.Context, id string) (store.Webhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w, ok := f.webhooks[id]
+	if !ok {
+		return store.Webhook{}, sql.ErrNoRows
+	}
+	return w, nil
+}
+
+func (f *fakeStore) ListWebhooks(ctx context.Context, filter store.Filter, pagination store.Pagination) (store.WebhookPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var page store.WebhookPage
+	for _, w := range f.webhooks {
+		page.Webhooks = append(page.Webhooks, w)
+	}
+	return page, nil
+}
+
+func (f *fakeStore) MarkProcessed(ctx context.Context, id, signature string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := id + "|" + signature
+	if f.processed[key] {
+		return true, nil
+	}
+	f.processed[key] = true
+	return false, nil
+}
+
+func (f *fakeStore) CreateSubscription(ctx context.Context, sub store.Subscription) (store.Subscription, error) {
+	return sub, nil
+}
+
+func (f *fakeStore) GetSubscription(ctx context.Context, id string) (store.Subscription, error) {
+	return store.Subscription{}, sql.ErrNoRows
+}
+
+func (f *fakeStore) ListSubscriptions(ctx context.Context) ([]store.Subscription, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) MatchingSubscriptions(ctx context.Context, cause string) ([]store.Subscription, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) UpdateSubscription(ctx context.Context, sub store.Subscription) (store.Subscription, error) {
+	return sub, nil
+}
+
+func (f *fakeStore) DeleteSubscription(ctx context.Context, id string) error { return nil }
+
+func (f *fakeStore) EnqueueDelivery(ctx context.Context, sub store.Subscription, payload, signable string) error {
+	return nil
+}
+
+func (f *fakeStore) DueDeliveries(ctx context.Context) ([]store.Delivery, error) { return nil, nil }
+
+func (f *fakeStore) GetDelivery(ctx context.Context, id string) (store.Delivery, error) {
+	return store.Delivery{}, sql.ErrNoRows
+}
+
+func (f *fakeStore) RecordAttempt(ctx context.Context, id string, status int, body string, delivered bool, nextAttemptAt time.Time) error {
+	return nil
+}
+
+func (f *fakeStore) ResetForRedelivery(ctx context.Context, id string) error { return nil }
+
+func (f *fakeStore) Close() error { return nil }
+
+const testSecret = "test-secret"
+
+func newTestHandler() (*Handler, *fakeStore) {
+	repo := newFakeStore()
+	svc := service.New(testSecret, repo, slog.New(slog.NewTextHandler(io.Discard, nil)), 0, 0, 0, false)
+	return NewHandler(svc), repo
+}
+
+func signedRequest(t *testing.T, h *Handler, webhook model.YayaWebhook, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(webhook)
+	if err != nil {
+		t.Fatalf("marshal webhook: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set("YAYA-SIGNATURE", signature)
+
+	rec := httptest.NewRecorder()
+	h.YayayWebhookHandler(rec, req)
+	return rec
+}
+
+func TestYayayWebhookHandler_AcceptsValidWebhook(t *testing.T) {
+	h, repo := newTestHandler()
+
+	webhook := model.YayaWebhook{
+		ID:            "wh-1",
+		Amount:        decimal.NewFromInt(100),
+		Currency:      model.ETB,
+		CreatedAtTime: time.Now().Unix(),
+		TimeStamp:     time.Now().Unix(),
+		Cause:         "deposit",
+		FullName:      "Jane Doe",
+		AccountName:   "jane",
+		InvoiceURL:    "https://example.com/invoice/1",
+	}
+
+	rec := signedRequest(t, h, webhook, signing.GenerateSignature(testSecret, webhook))
+
+	var resp model.Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Error != "" {
+		t.Fatalf("expected success response, got %+v", resp)
+	}
+
+	// saving happens asynchronously; give it a moment.
+	time.Sleep(50 * time.Millisecond)
+	repo.mu.Lock()
+	_, saved := repo.webhooks["wh-1"]
+	repo.mu.Unlock()
+	if !saved {
+		t.Fatal("expected webhook to be saved")
+	}
+}
+
+func TestYayayWebhookHandler_RejectsReplayedRequest(t *testing.T) {
+	h, _ := newTestHandler()
+
+	webhook := model.YayaWebhook{
+		ID:            "wh-2",
+		Amount:        decimal.NewFromInt(50),
+		Currency:      model.ETB,
+		CreatedAtTime: time.Now().Unix(),
+		TimeStamp:     time.Now().Unix(),
+		Cause:         "withdrawal",
+		FullName:      "John Roe",
+		AccountName:   "john",
+		InvoiceURL:    "https://example.com/invoice/2",
+	}
+	signature := signing.GenerateSignature(testSecret, webhook)
+
+	first := signedRequest(t, h, webhook, signature)
+	var firstResp model.Response
+	json.NewDecoder(first.Body).Decode(&firstResp)
+	if firstResp.Message != "Webhook received successfully" {
+		t.Fatalf("expected first request to succeed, got %+v", firstResp)
+	}
+
+	second := signedRequest(t, h, webhook, signature)
+	var secondResp model.Response
+	json.NewDecoder(second.Body).Decode(&secondResp)
+	if secondResp.Message != "duplicate, already processed" {
+		t.Fatalf("expected second request to be rejected as duplicate, got %+v", secondResp)
+	}
+}
+
+func TestYayayWebhookHandler_RejectsInvalidSignature(t *testing.T) {
+	h, _ := newTestHandler()
+
+	webhook := model.YayaWebhook{
+		ID:            "wh-3",
+		Amount:        decimal.NewFromInt(10),
+		Currency:      model.ETB,
+		CreatedAtTime: time.Now().Unix(),
+		TimeStamp:     time.Now().Unix(),
+		Cause:         "deposit",
+		FullName:      "Jane Doe",
+		AccountName:   "jane",
+		InvoiceURL:    "https://example.com/invoice/3",
+	}
+
+	rec := signedRequest(t, h, webhook, "deadbeef")
+
+	var resp model.Response
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Error != "invalid signature" {
+		t.Fatalf("expected invalid signature error, got %+v", resp)
+	}
+}
@@ -0,0 +1,297 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+)
+
+// API exposes the HTTP surface for managing subscriptions and triggering
+// manual redeliveries. Every handler requires the caller to present
+// apiKey as a bearer token, since this surface controls where financial
+// webhook payloads get forwarded and can read back signing secrets.
+type API struct {
+	repo   store.Repository
+	log    *slog.Logger
+	apiKey string
+}
+
+func NewAPI(repo store.Repository, log *slog.Logger, apiKey string) *API {
+	return &API{repo: repo, log: log, apiKey: apiKey}
+}
+
+// authorize reports whether r carries the configured admin API key in
+// its Authorization header ("Bearer <key>"). Comparison is constant-time
+// to avoid leaking the key through response-time side channels.
+func (a *API) authorize(r *http.Request) bool {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(strings.TrimPrefix(header, prefix)), []byte(a.apiKey))
+}
+
+func (a *API) requireAuth(w http.ResponseWriter, r *http.Request) bool {
+	if a.apiKey == "" || !a.authorize(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+type subscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// subscriptionResponse is what a subscription looks like over the wire.
+// Secret is intentionally omitted: once set it is only ever used
+// server-side to sign outbound deliveries, never read back.
+type subscriptionResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toSubscriptionResponse(sub store.Subscription) subscriptionResponse {
+	return subscriptionResponse{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    sub.Events,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+// SubscriptionsHandler handles listing and creating subscriptions.
+func (a *API) SubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	if !a.requireAuth(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		subs, err := a.repo.ListSubscriptions(r.Context())
+		if err != nil {
+			a.log.ErrorContext(r.Context(), "failed to list subscriptions", "error", err)
+			http.Error(w, "failed to list subscriptions", http.StatusInternalServerError)
+			return
+		}
+		responses := make([]subscriptionResponse, 0, len(subs))
+		for _, sub := range subs {
+			responses = append(responses, toSubscriptionResponse(sub))
+		}
+		writeJSON(w, http.StatusOK, responses)
+
+	case http.MethodPost:
+		var req subscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid subscription payload", http.StatusBadRequest)
+			return
+		}
+
+		if err := validateSubscriptionURL(req.URL); err != nil {
+			http.Error(w, fmt.Sprintf("invalid url: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		sub, err := a.repo.CreateSubscription(r.Context(), store.Subscription{
+			URL:    req.URL,
+			Events: req.Events,
+			Secret: req.Secret,
+		})
+		if err != nil {
+			a.log.ErrorContext(r.Context(), "failed to create subscription", "error", err)
+			http.Error(w, "failed to create subscription", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, toSubscriptionResponse(sub))
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SubscriptionHandler handles fetching, updating, and deleting a single
+// subscription.
+func (a *API) SubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	if !a.requireAuth(w, r) {
+		return
+	}
+
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		sub, err := a.repo.GetSubscription(r.Context(), id)
+		if err != nil {
+			http.Error(w, "subscription not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, toSubscriptionResponse(sub))
+
+	case http.MethodPut:
+		var req subscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid subscription payload", http.StatusBadRequest)
+			return
+		}
+
+		if err := validateSubscriptionURL(req.URL); err != nil {
+			http.Error(w, fmt.Sprintf("invalid url: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		sub, err := a.repo.UpdateSubscription(r.Context(), store.Subscription{
+			ID:     id,
+			URL:    req.URL,
+			Events: req.Events,
+			Secret: req.Secret,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "subscription not found", http.StatusNotFound)
+				return
+			}
+			a.log.ErrorContext(r.Context(), "failed to update subscription", "error", err)
+			http.Error(w, "failed to update subscription", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, toSubscriptionResponse(sub))
+
+	case http.MethodPatch:
+		var req subscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid subscription payload", http.StatusBadRequest)
+			return
+		}
+
+		existing, err := a.repo.GetSubscription(r.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "subscription not found", http.StatusNotFound)
+				return
+			}
+			a.log.ErrorContext(r.Context(), "failed to load subscription", "error", err)
+			http.Error(w, "failed to update subscription", http.StatusInternalServerError)
+			return
+		}
+
+		// PATCH only touches fields present in the request body; an
+		// omitted url/events/secret leaves the existing value alone
+		// instead of wiping it, since a partial patch that zeroes out
+		// the signing secret or narrows events unintentionally is a
+		// silent, hard-to-notice regression for the caller.
+		if req.URL != "" {
+			existing.URL = req.URL
+		}
+		if req.Events != nil {
+			existing.Events = req.Events
+		}
+		if req.Secret != "" {
+			existing.Secret = req.Secret
+		}
+
+		if err := validateSubscriptionURL(existing.URL); err != nil {
+			http.Error(w, fmt.Sprintf("invalid url: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		sub, err := a.repo.UpdateSubscription(r.Context(), existing)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "subscription not found", http.StatusNotFound)
+				return
+			}
+			a.log.ErrorContext(r.Context(), "failed to update subscription", "error", err)
+			http.Error(w, "failed to update subscription", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, toSubscriptionResponse(sub))
+
+	case http.MethodDelete:
+		if err := a.repo.DeleteSubscription(r.Context(), id); err != nil {
+			a.log.ErrorContext(r.Context(), "failed to delete subscription", "error", err)
+			http.Error(w, "failed to delete subscription", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RedeliverHandler forces an existing delivery to be retried immediately.
+func (a *API) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	if !a.requireAuth(w, r) {
+		return
+	}
+
+	id := r.PathValue("id")
+
+	if _, err := a.repo.GetDelivery(r.Context(), id); err != nil {
+		http.Error(w, "delivery not found", http.StatusNotFound)
+		return
+	}
+
+	if err := a.repo.ResetForRedelivery(r.Context(), id); err != nil {
+		a.log.ErrorContext(r.Context(), "failed to reset delivery", "error", err)
+		http.Error(w, "failed to schedule redelivery", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "redelivery scheduled"})
+}
+
+// validateSubscriptionURL rejects subscription URLs that would let a
+// caller use the delivery worker as an SSRF pivot against internal
+// infrastructure: non-http(s) schemes, and hosts that resolve to
+// loopback, private, or link-local addresses.
+func validateSubscriptionURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf("url must have a host")
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("could not resolve host: %w", err)
+	}
+
+	for _, ip := range ips {
+		if isDisallowedIP(ip) {
+			return fmt.Errorf("host resolves to a disallowed address: %s", ip)
+		}
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
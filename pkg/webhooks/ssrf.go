@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// isDisallowedIP reports whether ip is loopback, private, link-local, or
+// unspecified — addresses a subscription URL must never resolve to,
+// since the delivery worker sends every matching webhook (including
+// financial PII) and the per-subscription signing secret to it.
+func isDisallowedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// newDeliveryHTTPClient returns an http.Client for the delivery worker
+// that re-validates the destination address at connection time (closing
+// the DNS-rebinding gap a one-time check at subscription-creation would
+// leave open) and refuses to follow redirects, so a subscription can't
+// point deliveries at internal infrastructure after the fact.
+func newDeliveryHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil {
+				ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+				if err != nil {
+					return nil, err
+				}
+				if len(ips) == 0 {
+					return nil, fmt.Errorf("no addresses found for %s", host)
+				}
+				ip = ips[0]
+				addr = net.JoinHostPort(ip.String(), port)
+			}
+
+			if isDisallowedIP(ip) {
+				return nil, fmt.Errorf("refusing to deliver to disallowed address %s", ip)
+			}
+
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return fmt.Errorf("delivery requests do not follow redirects")
+		},
+	}
+}
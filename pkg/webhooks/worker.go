@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/NebilMuhe/yaya_webhook/pkg/metrics"
+	"github.com/NebilMuhe/yaya_webhook/pkg/signing"
+	"github.com/NebilMuhe/yaya_webhook/pkg/store"
+)
+
+// backoffSchedule is the delay applied after each failed attempt, indexed
+// by attempt number (1-based). Once an attempt exceeds maxAttempts the
+// delivery is abandoned.
+var backoffSchedule = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	1 * time.Hour,
+	6 * time.Hour,
+}
+
+const maxAttempts = 5
+
+// Worker polls the deliveries table for due deliveries and attempts to
+// POST them to their subscription's URL.
+type Worker struct {
+	repo   store.Repository
+	client *http.Client
+	log    *slog.Logger
+	poll   time.Duration
+}
+
+func NewWorker(repo store.Repository, log *slog.Logger) *Worker {
+	return &Worker{
+		repo:   repo,
+		client: newDeliveryHTTPClient(10 * time.Second),
+		log:    log,
+		poll:   5 * time.Second,
+	}
+}
+
+// Run blocks, polling for due deliveries until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.processDue(ctx)
+		}
+	}
+}
+
+func (w *Worker) processDue(ctx context.Context) {
+	due, err := w.repo.DueDeliveries(ctx)
+	if err != nil {
+		w.log.ErrorContext(ctx, "failed to load due deliveries", "error", err)
+		return
+	}
+	metrics.DeliveryQueueDepth.Set(float64(len(due)))
+
+	for _, delivery := range due {
+		w.attempt(ctx, delivery)
+	}
+}
+
+func (w *Worker) attempt(ctx context.Context, delivery store.Delivery) {
+	sub, err := w.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		w.log.ErrorContext(ctx, "failed to load subscription for delivery", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+
+	status, body, err := w.deliver(ctx, sub, delivery.Payload, delivery.Signable)
+	attempt := delivery.Attempt + 1
+	delivered := err == nil && status >= 200 && status < 300
+
+	if delivered {
+		if recErr := w.repo.RecordAttempt(ctx, delivery.ID, status, body, true, time.Time{}); recErr != nil {
+			w.log.ErrorContext(ctx, "failed to record successful delivery", "delivery_id", delivery.ID, "error", recErr)
+		}
+		return
+	}
+
+	if err != nil {
+		w.log.ErrorContext(ctx, "delivery attempt failed", "delivery_id", delivery.ID, "attempt", attempt, "error", err)
+	} else {
+		w.log.ErrorContext(ctx, "delivery attempt rejected", "delivery_id", delivery.ID, "attempt", attempt, "status", status)
+	}
+
+	if attempt >= maxAttempts {
+		w.log.ErrorContext(ctx, "delivery permanently failed", "delivery_id", delivery.ID, "attempts", attempt)
+		// Leave delivered_at unset but push next_attempt_at far into the
+		// future so DueDeliveries stops picking it up.
+		if recErr := w.repo.RecordAttempt(ctx, delivery.ID, status, body, false, time.Now().Add(24*365*time.Hour)); recErr != nil {
+			w.log.ErrorContext(ctx, "failed to record permanent failure", "delivery_id", delivery.ID, "error", recErr)
+		}
+		return
+	}
+
+	next := time.Now().Add(backoffSchedule[attempt-1])
+	if recErr := w.repo.RecordAttempt(ctx, delivery.ID, status, body, false, next); recErr != nil {
+		w.log.ErrorContext(ctx, "failed to reschedule delivery", "delivery_id", delivery.ID, "error", recErr)
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, sub store.Subscription, payload, signable string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewBufferString(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("YAYA-SIGNATURE", signing.Sign(sub.Secret, signable))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+
+	return resp.StatusCode, string(respBody), nil
+}